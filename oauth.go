@@ -66,7 +66,7 @@ func (c *Client) AccessTokenReq() error {
 func (c *Client) updateAccessTokenIfExp() error {
 	var err error
 
-	if c.oauth.ExpiresAt.Sub(time.Now().UTC()) < 60 {
+	if c.oauth.ExpiresAt.Sub(time.Now().UTC()) < time.Minute {
 		err = c.AccessTokenReq()
 		if err != nil {
 			return err
@@ -0,0 +1,95 @@
+package blizzard
+
+import "context"
+
+// defaultBatchConcurrency is used when BatchOptions.Concurrency is left unset
+const defaultBatchConcurrency = 10
+
+// BatchRequest is a single GET to fan out as part of a Batch
+type BatchRequest struct {
+	Path      string
+	Namespace string
+	Locale    Locale
+	Out       interface{}
+}
+
+// BatchOptions configures a Batch
+type BatchOptions struct {
+	// Concurrency caps how many BatchRequests are in flight at once. Defaults to
+	// defaultBatchConcurrency when left at zero.
+	Concurrency int
+}
+
+// Batch fans a slice of BatchRequests out to c, honoring Concurrency and the Client's rate
+// limiter, and returns their bodies in the same order they were added.
+type Batch struct {
+	c    *Client
+	ctx  context.Context
+	opts BatchOptions
+	reqs []BatchRequest
+}
+
+// Batch creates a new Batch of requests to run against c
+func (c *Client) Batch(ctx context.Context, opts BatchOptions) *Batch {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultBatchConcurrency
+	}
+
+	return &Batch{c: c, ctx: ctx, opts: opts}
+}
+
+// Add queues req to be fetched when Do is called
+func (b *Batch) Add(req BatchRequest) *Batch {
+	b.reqs = append(b.reqs, req)
+	return b
+}
+
+// batchResult is the outcome of a single BatchRequest
+type batchResult struct {
+	Body []byte
+	Err  error
+}
+
+// Do runs all queued requests, up to Concurrency at a time, decoding each into its
+// BatchRequest.Out, and returns their raw bodies in request order. The error of the
+// earliest failing request, in request order, is also returned, but every request is
+// still attempted.
+func (b *Batch) Do() ([]batchResult, error) {
+	results := make([]batchResult, len(b.reqs))
+	sem := make(chan struct{}, b.opts.Concurrency)
+	done := make(chan struct{}, len(b.reqs))
+
+	for i, req := range b.reqs {
+		i, req := i, req
+
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				done <- struct{}{}
+			}()
+
+			locale := req.Locale
+			if locale == "" {
+				locale = b.c.locale
+			}
+
+			_, body, err := b.c.getStructDataLocale(b.ctx, req.Path, req.Namespace, locale, req.Out)
+			results[i] = batchResult{Body: body, Err: err}
+		}()
+	}
+
+	for range b.reqs {
+		<-done
+	}
+
+	var firstErr error
+	for _, r := range results {
+		if r.Err != nil {
+			firstErr = r.Err
+			break
+		}
+	}
+
+	return results, firstErr
+}
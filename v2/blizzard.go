@@ -4,11 +4,12 @@ package blizzard
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
@@ -30,6 +31,45 @@ type Client struct {
 	dynamicClassicNamespace, staticClassicNamespace string
 	region                                          Region
 	locale                                          Locale
+	cache                                           Cache
+	baseTransport                                   http.RoundTripper
+	retryPolicy                                     retryPolicy
+	rateLimiter                                     *rateLimiter
+	requestHooks                                    []RequestHook
+	responseHooks                                   []ResponseHook
+	tokenStore                                      TokenStore
+	tokenRefreshHooks                               []func(*oauth2.Token)
+}
+
+// ClientOption configures optional Client behavior at construction time, via NewClient
+type ClientOption func(*Client)
+
+// WithRateLimit overrides the default token-bucket rate limiter (100 req/s, 36,000 req/hour,
+// matching Blizzard's documented per-client limits) with the given caps. A zero or negative
+// value keeps the corresponding default.
+func WithRateLimit(qps, hourlyCap int) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = newRateLimiter(qps, hourlyCap)
+	}
+}
+
+// WithRetryPolicy overrides the default retry policy (3 retries, 500ms base backoff) used by
+// the retryable transport for 429/500/502/503/504 responses.
+func WithRetryPolicy(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = retryPolicy{maxRetries: maxRetries, baseDelay: baseDelay}
+	}
+}
+
+// WithHTTPClient uses hc.Transport as the base transport underneath the OAuth2 and retry
+// transports, e.g. to plug in a custom net.Dialer. Other fields of hc, such as Timeout,
+// are not used.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		if hc != nil && hc.Transport != nil {
+			c.baseTransport = hc.Transport
+		}
+	}
 }
 
 // Region type
@@ -85,13 +125,15 @@ const (
 )
 
 // NewClient create new Blizzard structure. This structure will be used to acquire your access token and make API calls.
-func NewClient(clientID, clientSecret string, region Region, locale Locale) *Client {
+func NewClient(clientID, clientSecret string, region Region, locale Locale, opts ...ClientOption) *Client {
 	var c = Client{
 		oauth: OAuth{
 			ClientID:     clientID,
 			ClientSecret: clientSecret,
 		},
-		locale: locale,
+		locale:      locale,
+		retryPolicy: defaultRetryPolicy,
+		cache:       NewLRUCache(0),
 	}
 
 	c.cfg = clientcredentials.Config{
@@ -99,6 +141,16 @@ func NewClient(clientID, clientSecret string, region Region, locale Locale) *Cli
 		ClientSecret: c.oauth.ClientSecret,
 	}
 
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	// Only spin up the default rate limiter's refill goroutines if WithRateLimit didn't
+	// already install one, so replacing the default never leaks the one it replaced.
+	if c.rateLimiter == nil {
+		c.rateLimiter = newRateLimiter(defaultQPS, defaultHourlyCap)
+	}
+
 	c.SetRegion(region)
 
 	return &c
@@ -119,7 +171,9 @@ func (c *Client) GetRegion() Region {
 	return c.region
 }
 
-// SetRegion changes the Region of the client
+// SetRegion changes the Region of the client. This rebuilds the underlying *http.Client
+// from scratch, which silently drops any middleware chain previously installed with
+// Client.Use — re-call Use afterwards if the Client stays alive across a region change.
 func (c *Client) SetRegion(region Region) {
 	c.region = region
 
@@ -143,7 +197,43 @@ func (c *Client) SetRegion(region Region) {
 	}
 
 	c.cfg.TokenURL = c.oauthHost + "/oauth/token"
-	c.client = c.cfg.Client(context.Background())
+	c.client = c.buildHTTPClient()
+}
+
+// buildHTTPClient wraps the client credentials OAuth2 transport around the retryable
+// transport and the user-supplied base transport (if any), so retries and custom dialers
+// apply to every request sent through c.client. getStructDataOAuth, Exchange and
+// TokenSource do not use c.client: they take their *http.Client from the ctx they are
+// given. The token source is seeded from the TokenStore (if one is configured and has a
+// still-valid token saved); every refreshed token is saved back to it and passed to the
+// OnTokenRefresh callbacks, including callbacks registered after this client was built.
+func (c *Client) buildHTTPClient() *http.Client {
+	base := c.baseTransport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+		Transport: &retryTransport{base: base, policy: c.retryPolicy},
+	})
+
+	var seed *oauth2.Token
+	if c.tokenStore != nil {
+		if tok, err := c.tokenStore.Load(); err == nil {
+			seed = tok
+		}
+	}
+
+	src := oauth2.ReuseTokenSource(seed, c.cfg.TokenSource(ctx))
+
+	// Wrapped unconditionally, not via withTokenPersistence: OnTokenRefresh can only be
+	// called once NewClient has built this client, and persistingTokenSource reads the
+	// Client's hooks at refresh time.
+	return oauth2.NewClient(ctx, oauth2.ReuseTokenSource(nil, &persistingTokenSource{
+		base:   src,
+		store:  c.tokenStore,
+		client: c,
+	}))
 }
 
 // GetRegion returns the Region of the client
@@ -181,8 +271,26 @@ func (c *Client) GetStaticClassicNamespace() string {
 	return c.staticClassicNamespace
 }
 
+// Close stops background goroutines owned by the Client, namely the rate limiter's token
+// bucket refills. Call it when the Client is no longer needed.
+func (c *Client) Close() {
+	c.rateLimiter.close()
+}
+
 // getStructData processes simple GET request based on pathAndQuery an returns the structured data.
 func (c *Client) getStructData(ctx context.Context, pathAndQuery, namespace string, dat interface{}) (interface{}, []byte, error) {
+	return c.getStructDataLocale(ctx, pathAndQuery, namespace, c.locale, dat)
+}
+
+// getStructDataLocale is getStructData with an explicit Locale override, used by Batch to
+// let individual BatchRequests use a Locale other than the Client's default.
+func (c *Client) getStructDataLocale(ctx context.Context, pathAndQuery, namespace string, locale Locale, dat interface{}) (interface{}, []byte, error) {
+	key := cacheKey("GET", pathAndQuery, namespace, locale.String(), "")
+
+	if err := c.rateLimiter.wait(ctx); err != nil {
+		return dat, nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", c.apiHost+pathAndQuery, nil)
 	if err != nil {
 		return dat, nil, err
@@ -191,26 +299,47 @@ func (c *Client) getStructData(ctx context.Context, pathAndQuery, namespace stri
 	req.Header.Set("Accept", "application/json")
 
 	q := req.URL.Query()
-	q.Set("locale", c.locale.String())
+	q.Set("locale", locale.String())
 	req.URL.RawQuery = q.Encode()
 
 	if namespace != "" {
 		req.Header.Set("Battlenet-Namespace", namespace)
 	}
 
+	cached, hasCached := c.cacheGet(ctx, key)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.ETag)
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	c.runRequestHooks(req)
+
 	res, err := c.client.Do(req)
 	if err != nil {
 		return dat, nil, err
 	}
 	defer res.Body.Close()
 
+	c.runResponseHooks(res)
+
+	if hasCached && res.StatusCode == http.StatusNotModified {
+		// Decode into the caller-supplied dat, not just cached.Value, so callers that rely
+		// on the interface-indirection trick (e.g. Batch, which discards the returned
+		// value) still see the struct through their own pointer on a 304.
+		if err := json.Unmarshal(cached.Body, &dat); err != nil {
+			return dat, cached.Body, err
+		}
+
+		return dat, cached.Body, nil
+	}
+
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return dat, nil, err
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return dat, body, errors.New(res.Status)
+		return dat, body, newAPIError(res.StatusCode, body)
 	}
 
 	err = json.Unmarshal(body, &dat)
@@ -218,12 +347,20 @@ func (c *Client) getStructData(ctx context.Context, pathAndQuery, namespace stri
 		return dat, body, err
 	}
 
+	c.cacheSet(key, res, body, dat)
+
 	return dat, body, nil
 }
 
 // getStructDataNoLocale processes simple GET request based on pathAndQuery an returns the structured data.
 // Does not use a Locale.
 func (c *Client) getStructDataNoLocale(ctx context.Context, pathAndQuery, namespace string, dat interface{}) (interface{}, []byte, error) {
+	key := cacheKey("GET", pathAndQuery, namespace, "", "")
+
+	if err := c.rateLimiter.wait(ctx); err != nil {
+		return dat, nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", c.apiHost+pathAndQuery, nil)
 	if err != nil {
 		return dat, nil, err
@@ -235,19 +372,40 @@ func (c *Client) getStructDataNoLocale(ctx context.Context, pathAndQuery, namesp
 		req.Header.Set("Battlenet-Namespace", namespace)
 	}
 
+	cached, hasCached := c.cacheGet(ctx, key)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.ETag)
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	c.runRequestHooks(req)
+
 	res, err := c.client.Do(req)
 	if err != nil {
 		return dat, nil, err
 	}
 	defer res.Body.Close()
 
+	c.runResponseHooks(res)
+
+	if hasCached && res.StatusCode == http.StatusNotModified {
+		// Decode into the caller-supplied dat, not just cached.Value, so callers that rely
+		// on the interface-indirection trick (e.g. Batch, which discards the returned
+		// value) still see the struct through their own pointer on a 304.
+		if err := json.Unmarshal(cached.Body, &dat); err != nil {
+			return dat, cached.Body, err
+		}
+
+		return dat, cached.Body, nil
+	}
+
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return dat, nil, err
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return dat, body, errors.New(res.Status)
+		return dat, body, newAPIError(res.StatusCode, body)
 	}
 
 	err = json.Unmarshal(body, &dat)
@@ -255,6 +413,8 @@ func (c *Client) getStructDataNoLocale(ctx context.Context, pathAndQuery, namesp
 		return dat, body, err
 	}
 
+	c.cacheSet(key, res, body, dat)
+
 	return dat, body, nil
 }
 
@@ -262,6 +422,12 @@ func (c *Client) getStructDataNoLocale(ctx context.Context, pathAndQuery, namesp
 // Uses OAuth2.
 func (c *Client) getStructDataOAuth(ctx context.Context, pathAndQuery, namespace string,
 	token *oauth2.Token, dat interface{}) (interface{}, []byte, error) {
+	key := cacheKey("GET", pathAndQuery, namespace, c.locale.String(), token.AccessToken)
+
+	if err := c.rateLimiter.wait(ctx); err != nil {
+		return dat, nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", c.apiHost+pathAndQuery, nil)
 	if err != nil {
 		return dat, nil, err
@@ -277,7 +443,15 @@ func (c *Client) getStructDataOAuth(ctx context.Context, pathAndQuery, namespace
 		req.Header.Set("Battlenet-Namespace", namespace)
 	}
 
-	client := c.authorizedCfg.Client(context.Background(), token)
+	cached, hasCached := c.cacheGet(ctx, key)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.ETag)
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	client := oauth2.NewClient(ctx, c.TokenSource(ctx, token))
+
+	c.runRequestHooks(req)
 
 	res, err := client.Do(req)
 	if err != nil {
@@ -285,13 +459,26 @@ func (c *Client) getStructDataOAuth(ctx context.Context, pathAndQuery, namespace
 	}
 	defer res.Body.Close()
 
+	c.runResponseHooks(res)
+
+	if hasCached && res.StatusCode == http.StatusNotModified {
+		// Decode into the caller-supplied dat, not just cached.Value, so callers that rely
+		// on the interface-indirection trick (e.g. Batch, which discards the returned
+		// value) still see the struct through their own pointer on a 304.
+		if err := json.Unmarshal(cached.Body, &dat); err != nil {
+			return dat, cached.Body, err
+		}
+
+		return dat, cached.Body, nil
+	}
+
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return dat, nil, err
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return dat, body, errors.New(res.Status)
+		return dat, body, newAPIError(res.StatusCode, body)
 	}
 
 	err = json.Unmarshal(body, &dat)
@@ -299,9 +486,57 @@ func (c *Client) getStructDataOAuth(ctx context.Context, pathAndQuery, namespace
 		return dat, body, err
 	}
 
+	c.cacheSet(key, res, body, dat)
+
 	return dat, body, nil
 }
 
+// cacheGet returns the cached entry for key, unless the Client has no Cache configured or
+// the request context opted out via WithCacheBypass.
+func (c *Client) cacheGet(ctx context.Context, key string) (CacheEntry, bool) {
+	if c.cache == nil || cacheBypassed(ctx) {
+		return CacheEntry{}, false
+	}
+
+	return c.cache.Get(key)
+}
+
+// cacheSet stores res's ETag/Last-Modified alongside body and the decoded dat, if the
+// Client has a Cache configured.
+func (c *Client) cacheSet(key string, res *http.Response, body []byte, dat interface{}) {
+	if c.cache == nil {
+		return
+	}
+
+	c.cache.Set(key, CacheEntry{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		Body:         body,
+		Value:        cloneStruct(dat, body),
+	})
+}
+
+// cloneStruct decodes body into a freshly allocated value of dat's underlying type for
+// CacheEntry.Value, so the Cache never holds the exact pointer handed back to a caller.
+// The getStruct* helpers themselves never read Value back (they re-decode Body on every
+// hit), so this only matters to callers that read CacheEntry.Value from a custom Cache
+// backend; without it, they'd see one caller's in-progress mutations to its own copy of dat
+// bleed into the cached entry. Falls back to dat itself if it isn't a pointer or body
+// doesn't decode, which only matters for callers that hand cacheSet something unusual.
+func cloneStruct(dat interface{}, body []byte) interface{} {
+	v := reflect.ValueOf(dat)
+	if v.Kind() != reflect.Ptr {
+		return dat
+	}
+
+	clone := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal(body, clone.Interface()); err != nil {
+		return dat
+	}
+
+	return clone.Interface()
+}
+
 func formatAccount(account string) string {
 	return strings.Replace(account, "#", "-", 1)
 }
@@ -0,0 +1,127 @@
+package blizzard
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// CacheEntry is a cached HTTP response. The getStruct* helpers only ever read ETag,
+// LastModified, and Body back (they re-decode Body into the caller's struct on every hit);
+// Value holds a decoded copy purely for custom Cache backends that want to introspect or
+// serialize it without re-parsing Body themselves.
+type CacheEntry struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+	Value        interface{}
+}
+
+// Cache is a pluggable store for CacheEntry values, keyed by the request identity computed
+// by cacheKey. The default is an in-memory LRU, but Redis, disk, or any other backend can
+// be plugged in via Client.WithCache.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+}
+
+// lruCache is the default in-memory Cache implementation
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type lruCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUCache creates an in-memory Cache holding at most capacity entries, evicting the
+// least recently used entry once full.
+func NewLRUCache(capacity int) Cache {
+	if capacity <= 0 {
+		capacity = 256
+	}
+
+	return &lruCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	c.ll.MoveToFront(el)
+
+	return el.Value.(*lruCacheItem).entry, true
+}
+
+func (c *lruCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*lruCacheItem).entry = entry
+		return
+	}
+
+	el := c.ll.PushFront(&lruCacheItem{key: key, entry: entry})
+	c.items[key] = el
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruCacheItem).key)
+		}
+	}
+}
+
+// WithCache sets the Cache used by the Client for GET requests made through
+// getStructData, getStructDataNoLocale, and getStructDataOAuth
+func (c *Client) WithCache(cache Cache) *Client {
+	c.cache = cache
+	return c
+}
+
+type cacheBypassKey struct{}
+
+// WithCacheBypass returns a context that skips the Cache lookup for the request it
+// decorates; a 200 OK response to that request is still stored in the Cache.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// cacheKey hashes the parts of a request that make its response unique
+func cacheKey(method, pathAndQuery, namespace, locale, scopeHint string) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(pathAndQuery))
+	h.Write([]byte{0})
+	h.Write([]byte(namespace))
+	h.Write([]byte{0})
+	h.Write([]byte(locale))
+	h.Write([]byte{0})
+	h.Write([]byte(scopeHint))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
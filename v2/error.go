@@ -0,0 +1,34 @@
+package blizzard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned by the getStructData* helpers whenever Blizzard responds with a
+// non-200 status. It carries the parsed Blizzard error body, if any, so callers can branch
+// on 404 vs 403 vs 429 instead of string-matching an *errors.errorString.
+type APIError struct {
+	StatusCode int
+	Code       int    `json:"code"`
+	Detail     string `json:"detail"`
+}
+
+func (e *APIError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("blizzard: %s: %s", http.StatusText(e.StatusCode), e.Detail)
+	}
+
+	return fmt.Sprintf("blizzard: %s", http.StatusText(e.StatusCode))
+}
+
+// newAPIError builds an *APIError from a non-200 response's status code and body, best
+// effort decoding Blizzard's {"code":...,"detail":...} error payload.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	_ = json.Unmarshal(body, apiErr)
+
+	return apiErr
+}
@@ -0,0 +1,167 @@
+package blizzard
+
+import (
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"time"
+)
+
+// Middleware wraps an http.RoundTripper with additional behavior, e.g. logging or metrics.
+// Middleware is applied in the order passed to Client.Use, with the first Middleware
+// running outermost.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// Use wraps the Client's underlying transport with the given middleware chain. Use must be
+// called after the Client has been constructed, since NewClient/SetRegion otherwise
+// overwrite the transport. Calling SetRegion again after Use silently rebuilds the
+// transport from scratch and drops the chain installed here; re-call Use after any later
+// SetRegion if the Client is long-lived and can change region.
+func (c *Client) Use(mw ...Middleware) {
+	rt := c.client.Transport
+
+	for i := len(mw) - 1; i >= 0; i-- {
+		rt = mw[i](rt)
+	}
+
+	c.client.Transport = rt
+}
+
+// RequestHook is invoked with every outgoing request before it is sent, e.g. to inject a
+// custom User-Agent or sniff X-RateLimit-* headers on the following ResponseHook.
+type RequestHook func(*http.Request)
+
+// ResponseHook is invoked with every response the Client receives
+type ResponseHook func(*http.Response)
+
+// OnRequest registers a RequestHook to run before every request the Client sends
+func (c *Client) OnRequest(hook RequestHook) {
+	c.requestHooks = append(c.requestHooks, hook)
+}
+
+// OnResponse registers a ResponseHook to run after every response the Client receives
+func (c *Client) OnResponse(hook ResponseHook) {
+	c.responseHooks = append(c.responseHooks, hook)
+}
+
+// runRequestHooks invokes every registered RequestHook against req
+func (c *Client) runRequestHooks(req *http.Request) {
+	for _, hook := range c.requestHooks {
+		hook(req)
+	}
+}
+
+// runResponseHooks invokes every registered ResponseHook against res
+func (c *Client) runResponseHooks(res *http.Response) {
+	for _, hook := range c.responseHooks {
+		hook(res)
+	}
+}
+
+type loggingTransport struct {
+	base   http.RoundTripper
+	logger *log.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.logger.Printf("%s %s error=%v duration=%s", req.Method, req.URL.Path, err, time.Since(start))
+		return res, err
+	}
+
+	t.logger.Printf("%s %s status=%d bytes=%d duration=%s", req.Method, req.URL.Path, res.StatusCode, res.ContentLength, time.Since(start))
+
+	return res, err
+}
+
+// LoggingMiddleware logs the method, path, status, response size, and duration of every
+// request. A nil logger defaults to log.New(os.Stderr, "", log.LstdFlags).
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+
+	return func(base http.RoundTripper) http.RoundTripper {
+		return &loggingTransport{base: base, logger: logger}
+	}
+}
+
+// MetricsRecorder receives an observation for every completed request. Implement this
+// against your metrics library of choice (Prometheus, OpenTelemetry, StatsD, ...) rather
+// than requiring one as a direct dependency of this package. Ready-made Middleware for
+// Prometheus and OpenTelemetry ship as separate modules under v2/middleware/, so using
+// either doesn't pull its dependency tree into every consumer of this package.
+type MetricsRecorder interface {
+	Observe(path string, statusCode int, duration time.Duration)
+}
+
+type metricsTransport struct {
+	base     http.RoundTripper
+	recorder MetricsRecorder
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	res, err := t.base.RoundTrip(req)
+
+	status := 0
+	if res != nil {
+		status = res.StatusCode
+	}
+
+	t.recorder.Observe(req.URL.Path, status, time.Since(start))
+
+	return res, err
+}
+
+// MetricsMiddleware reports every request's path, status code, and duration to recorder,
+// e.g. a Prometheus histogram vector or an OpenTelemetry span-recording wrapper.
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return &metricsTransport{base: base, recorder: recorder}
+	}
+}
+
+type bodyDumpTransport struct {
+	base    http.RoundTripper
+	logger  *log.Logger
+	enabled bool
+}
+
+func (t *bodyDumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.enabled {
+		return t.base.RoundTrip(req)
+	}
+
+	if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+		t.logger.Printf("--> %s", dump)
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	if dump, err := httputil.DumpResponse(res, true); err == nil {
+		t.logger.Printf("<-- %s", dump)
+	}
+
+	return res, err
+}
+
+// BodyDumpMiddleware logs the full request and response bodies when debug is true. Intended
+// for local debugging only, since it defeats streaming and can log sensitive headers.
+func BodyDumpMiddleware(logger *log.Logger, debug bool) Middleware {
+	if logger == nil {
+		logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+
+	return func(base http.RoundTripper) http.RoundTripper {
+		return &bodyDumpTransport{base: base, logger: logger, enabled: debug}
+	}
+}
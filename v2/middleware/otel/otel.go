@@ -0,0 +1,83 @@
+// Package otel provides an optional Client.Use middleware that starts an OpenTelemetry
+// client span for every request. It's a separate module from
+// github.com/FuzzyStatic/blizzard/v2 so that pulling in go.opentelemetry.io/otel is
+// opt-in, not forced on every consumer of the core package.
+package otel
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type transport struct {
+	base   http.RoundTripper
+	tracer trace.Tracer
+	region string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), endpointFamily(req.URL.Path), trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("battle.net.region", t.region),
+		attribute.String("battle.net.namespace", req.Header.Get("Battlenet-Namespace")),
+		attribute.String("http.method", req.Method),
+		attribute.String("http.url", req.URL.String()),
+	)
+
+	res, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return res, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", res.StatusCode))
+	if res.StatusCode >= 400 {
+		span.SetStatus(codes.Error, res.Status)
+	}
+
+	return res, err
+}
+
+// Middleware starts a client span for every request via tracer, tagged with
+// battle.net.region (region, e.g. from (*blizzard.Client).GetRegion().String()) and
+// battle.net.namespace (read from the Battlenet-Namespace header blizzard's getStruct*
+// helpers already set) alongside the usual HTTP attributes, and records the response status
+// or error on the span. region is captured once, at Use time; re-apply this middleware
+// after a later (*blizzard.Client).SetRegion call to keep the attribute accurate. Pass the
+// result to (*blizzard.Client).Use.
+func Middleware(tracer trace.Tracer, region string) func(http.RoundTripper) http.RoundTripper {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return &transport{base: base, tracer: tracer, region: region}
+	}
+}
+
+// endpointFamily collapses purely numeric path segments into "{id}", grouping requests like
+// "/data/sc2/ladder/12345" and "/data/sc2/ladder/67890" under the same family.
+func endpointFamily(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if seg != "" && isNumericSegment(seg) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func isNumericSegment(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+// Package prometheus provides an optional Client.Use middleware that reports request
+// duration to Prometheus. It's a separate module from github.com/FuzzyStatic/blizzard/v2
+// so that pulling in github.com/prometheus/client_golang is opt-in, not forced on every
+// consumer of the core package.
+package prometheus
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type transport struct {
+	base http.RoundTripper
+	hist *prometheus.HistogramVec
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	res, err := t.base.RoundTrip(req)
+
+	status := "error"
+	if res != nil {
+		status = strconv.Itoa(res.StatusCode)
+	}
+
+	t.hist.WithLabelValues(endpointFamily(req.URL.Path), status).Observe(time.Since(start).Seconds())
+
+	return res, err
+}
+
+// Middleware observes request duration in hist, labeled by endpoint family (the request
+// path with numeric ID segments collapsed to "{id}", e.g. "/data/sc2/ladder/{id}") and
+// response status code. Collapsing IDs keeps label cardinality bounded to the API's route
+// shapes instead of one series per ID ever requested. hist must already be registered with
+// a *prometheus.Registry and declare an "endpoint" and a "status" label. Pass the result to
+// (*blizzard.Client).Use.
+func Middleware(hist *prometheus.HistogramVec) func(http.RoundTripper) http.RoundTripper {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return &transport{base: base, hist: hist}
+	}
+}
+
+// endpointFamily collapses purely numeric path segments into "{id}", grouping requests like
+// "/data/sc2/ladder/12345" and "/data/sc2/ladder/67890" under the same family.
+func endpointFamily(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if seg != "" && isNumericSegment(seg) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func isNumericSegment(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
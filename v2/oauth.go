@@ -0,0 +1,128 @@
+package blizzard
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"golang.org/x/oauth2"
+)
+
+// Scope is an OAuth 2.0 scope requested for the Authorization Code flow
+type Scope string
+
+// Scope constants supported by Blizzard's Authorization Code flow
+const (
+	ScopeOpenID     = Scope("openid")
+	ScopeWoWProfile = Scope("wow.profile")
+	ScopeSC2Profile = Scope("sc2.profile")
+	ScopeD3Profile  = Scope("d3.profile")
+)
+
+// NewAuthorizedClient creates a new Blizzard structure configured for the OAuth 2.0
+// Authorization Code flow, in addition to the client credentials flow NewClient already
+// supports. redirectURI must match one of the redirect URIs registered for the client on
+// the Blizzard developer portal. opts are forwarded to NewClient, so WithTokenStore,
+// WithRateLimit, WithRetryPolicy, and WithHTTPClient are all available here too.
+func NewAuthorizedClient(clientID, clientSecret, redirectURI string, region Region, locale Locale, scopes []Scope, opts ...ClientOption) *Client {
+	c := NewClient(clientID, clientSecret, region, locale, opts...)
+
+	scopeStrs := make([]string, len(scopes))
+	for i, s := range scopes {
+		scopeStrs[i] = string(s)
+	}
+
+	c.authorizedCfg = oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURI,
+		Scopes:       scopeStrs,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  c.oauthHost + "/oauth/authorize",
+			TokenURL: c.oauthHost + "/oauth/token",
+		},
+	}
+
+	return c
+}
+
+// AuthCodeURL returns a URL to redirect a user to Blizzard's consent page to ask for the
+// given scopes. State is a token to protect the user from CSRF attacks and is required.
+// Use PKCE.ChallengeOpts to add PKCE parameters.
+func (c *Client) AuthCodeURL(state string, scopes []Scope, opts ...oauth2.AuthCodeOption) string {
+	scopeStrs := make([]string, len(scopes))
+	for i, s := range scopes {
+		scopeStrs[i] = string(s)
+	}
+	c.authorizedCfg.Scopes = scopeStrs
+
+	return c.authorizedCfg.AuthCodeURL(state, opts...)
+}
+
+// Exchange converts an authorization code into an *oauth2.Token. Pass PKCE.VerifierOpt if
+// the initial AuthCodeURL was generated with PKCE.
+func (c *Client) Exchange(ctx context.Context, code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	return c.authorizedCfg.Exchange(ctx, code, opts...)
+}
+
+// TokenSource returns an oauth2.TokenSource that transparently refreshes tok as needed.
+// getStructDataOAuth builds its HTTP client from this TokenSource for the token it is
+// given. Refreshed tokens are persisted via the Client's
+// TokenStore and OnTokenRefresh callbacks, if configured.
+func (c *Client) TokenSource(ctx context.Context, tok *oauth2.Token) oauth2.TokenSource {
+	return c.withTokenPersistence(c.authorizedCfg.TokenSource(ctx, tok))
+}
+
+// TokenSourceFromStore returns an oauth2.TokenSource seeded from the Client's TokenStore, so
+// a token obtained through the Authorization Code flow before a process restart is picked
+// back up instead of forcing the user through the consent screen again. It returns
+// ErrNoToken if the Client has no TokenStore configured, or whatever the TokenStore's Load
+// returns if nothing has been saved yet.
+func (c *Client) TokenSourceFromStore(ctx context.Context) (oauth2.TokenSource, error) {
+	if c.tokenStore == nil {
+		return nil, ErrNoToken
+	}
+
+	tok, err := c.tokenStore.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.TokenSource(ctx, tok), nil
+}
+
+// PKCE holds a PKCE code verifier and its S256 code challenge, as described in RFC 7636.
+type PKCE struct {
+	Verifier  string
+	Challenge string
+}
+
+// NewPKCE generates a new random PKCE verifier and its corresponding S256 challenge.
+func NewPKCE() (*PKCE, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(raw)
+
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return &PKCE{Verifier: verifier, Challenge: challenge}, nil
+}
+
+// ChallengeOpts returns the oauth2.AuthCodeOptions to pass to AuthCodeURL to request this
+// PKCE challenge be honored.
+func (p *PKCE) ChallengeOpts() []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", p.Challenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	}
+}
+
+// VerifierOpt returns the oauth2.AuthCodeOption to pass to Exchange to prove ownership of
+// the code_challenge sent to AuthCodeURL.
+func (p *PKCE) VerifierOpt() oauth2.AuthCodeOption {
+	return oauth2.SetAuthURLParam("code_verifier", p.Verifier)
+}
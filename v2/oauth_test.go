@@ -0,0 +1,91 @@
+package blizzard
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestNewPKCEGeneratesMatchingChallenge(t *testing.T) {
+	p, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE() error = %v", err)
+	}
+
+	if p.Verifier == "" {
+		t.Fatal("Verifier is empty")
+	}
+	if p.Challenge == "" {
+		t.Fatal("Challenge is empty")
+	}
+
+	sum := sha256.Sum256([]byte(p.Verifier))
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+	if p.Challenge != want {
+		t.Errorf("Challenge = %q, want S256(Verifier) = %q", p.Challenge, want)
+	}
+}
+
+func TestNewPKCEIsRandom(t *testing.T) {
+	a, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE() error = %v", err)
+	}
+
+	b, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE() error = %v", err)
+	}
+
+	if a.Verifier == b.Verifier {
+		t.Error("two successive NewPKCE() calls returned the same Verifier")
+	}
+	if a.Challenge == b.Challenge {
+		t.Error("two successive NewPKCE() calls returned the same Challenge")
+	}
+}
+
+func TestPKCEChallengeOpts(t *testing.T) {
+	p := &PKCE{Verifier: "verifier-value", Challenge: "challenge-value"}
+
+	cfg := oauth2.Config{
+		Endpoint: oauth2.Endpoint{AuthURL: "https://example.com/oauth/authorize"},
+	}
+
+	authURL := cfg.AuthCodeURL("state", p.ChallengeOpts()...)
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", authURL, err)
+	}
+
+	q := u.Query()
+	if got := q.Get("code_challenge"); got != "challenge-value" {
+		t.Errorf("code_challenge = %q, want %q", got, "challenge-value")
+	}
+	if got := q.Get("code_challenge_method"); got != "S256" {
+		t.Errorf("code_challenge_method = %q, want %q", got, "S256")
+	}
+}
+
+func TestPKCEVerifierOpt(t *testing.T) {
+	p := &PKCE{Verifier: "verifier-value", Challenge: "challenge-value"}
+
+	cfg := oauth2.Config{
+		Endpoint: oauth2.Endpoint{AuthURL: "https://example.com/oauth/authorize"},
+	}
+
+	authURL := cfg.AuthCodeURL("state", p.VerifierOpt())
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", authURL, err)
+	}
+
+	if got := u.Query().Get("code_verifier"); got != "verifier-value" {
+		t.Errorf("code_verifier = %q, want %q", got, "verifier-value")
+	}
+}
@@ -0,0 +1,194 @@
+package blizzard
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultQPS and defaultHourlyCap mirror Blizzard's default per-client limits of 100
+// requests/second and 36,000 requests/hour
+const (
+	defaultQPS       = 100
+	defaultHourlyCap = 36000
+)
+
+// rateLimiter gates outgoing requests to stay under a per-second and an hourly cap. It is
+// intentionally simple (two token buckets, each refilled to full by its own ticking
+// goroutine) rather than pulling in golang.org/x/time/rate.
+type rateLimiter struct {
+	perSecond chan struct{}
+	perHour   chan struct{}
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func newRateLimiter(qps, hourlyCap int) *rateLimiter {
+	if qps <= 0 {
+		qps = defaultQPS
+	}
+	if hourlyCap <= 0 {
+		hourlyCap = defaultHourlyCap
+	}
+
+	rl := &rateLimiter{
+		perSecond: make(chan struct{}, qps),
+		perHour:   make(chan struct{}, hourlyCap),
+		stop:      make(chan struct{}),
+	}
+
+	for i := 0; i < qps; i++ {
+		rl.perSecond <- struct{}{}
+	}
+	for i := 0; i < hourlyCap; i++ {
+		rl.perHour <- struct{}{}
+	}
+
+	go rl.refill(rl.perSecond, qps, time.Second)
+	go rl.refill(rl.perHour, hourlyCap, time.Hour)
+
+	return rl
+}
+
+// close stops the refill goroutines started by newRateLimiter. Safe to call more than once.
+func (rl *rateLimiter) close() {
+	rl.stopOnce.Do(func() {
+		close(rl.stop)
+	})
+}
+
+func (rl *rateLimiter) refill(bucket chan struct{}, size int, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rl.stop:
+			return
+		case <-ticker.C:
+			for len(bucket) < size {
+				select {
+				case bucket <- struct{}{}:
+				default:
+					// Another goroutine raced us and filled the bucket; nothing left to top up.
+				}
+			}
+		}
+	}
+}
+
+// wait blocks until both the per-second and hourly buckets have a token available, or ctx
+// is canceled
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	select {
+	case <-rl.perHour:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-rl.perSecond:
+		return nil
+	case <-ctx.Done():
+		// Give back the hourly token we already took; otherwise a canceled/timed-out
+		// caller erodes the hourly cap without ever making a request.
+		select {
+		case rl.perHour <- struct{}{}:
+		default:
+		}
+
+		return ctx.Err()
+	}
+}
+
+// retryPolicy configures the retryTransport
+type retryPolicy struct {
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{maxRetries: 3, baseDelay: 500 * time.Millisecond}
+
+// retryTransport retries requests that fail with a 429, 500, 502, 503 or 504 status, honoring
+// Retry-After (seconds or an HTTP-date) when present and otherwise backing off
+// exponentially with jitter.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy retryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var (
+		res *http.Response
+		err error
+	)
+
+	for attempt := 0; attempt <= t.policy.maxRetries; attempt++ {
+		res, err = base.RoundTrip(req)
+		if err != nil {
+			return res, err
+		}
+
+		if !isRetryableStatus(res.StatusCode) || attempt == t.policy.maxRetries {
+			return res, nil
+		}
+
+		delay := retryDelay(res, attempt, t.policy.baseDelay)
+
+		res.Body.Close()
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return res, req.Context().Err()
+		}
+	}
+
+	return res, err
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay honors a Retry-After header when present, falling back to exponential
+// backoff with jitter
+func retryDelay(res *http.Response, attempt int, baseDelay time.Duration) time.Duration {
+	if ra := res.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+
+		if when, err := http.ParseTime(ra); err == nil {
+			if d := time.Until(when); d > 0 {
+				return d
+			}
+		}
+	}
+
+	backoff := baseDelay << attempt
+	if baseDelay <= 0 {
+		return backoff
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(baseDelay)))
+
+	return backoff + jitter
+}
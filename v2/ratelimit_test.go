@@ -0,0 +1,151 @@
+package blizzard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryDelayHonorsRetryAfterSeconds(t *testing.T) {
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+
+	got := retryDelay(res, 0, 500*time.Millisecond)
+	if want := 2 * time.Second; got != want {
+		t.Errorf("retryDelay() = %v, want %v", got, want)
+	}
+}
+
+func TestRetryDelayHonorsRetryAfterHTTPDate(t *testing.T) {
+	when := time.Now().Add(3 * time.Second)
+	res := &http.Response{Header: http.Header{"Retry-After": []string{when.UTC().Format(http.TimeFormat)}}}
+
+	got := retryDelay(res, 0, 500*time.Millisecond)
+	if got <= 0 || got > 3*time.Second {
+		t.Errorf("retryDelay() = %v, want a positive duration up to 3s", got)
+	}
+}
+
+func TestRetryDelayBacksOffExponentially(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+
+	for attempt := 0; attempt < 4; attempt++ {
+		d := retryDelay(res, attempt, 100*time.Millisecond)
+		min := 100 * time.Millisecond << attempt
+		max := min + 100*time.Millisecond
+
+		if d < min || d >= max {
+			t.Errorf("retryDelay(attempt=%d) = %v, want in [%v, %v)", attempt, d, min, max)
+		}
+	}
+}
+
+func TestRetryDelayDoesNotPanicOnZeroBaseDelay(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+
+	if got := retryDelay(res, 2, 0); got != 0 {
+		t.Errorf("retryDelay() = %v, want 0", got)
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableStatus(tt.status); got != tt.want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRetryTransportRetriesUntilSuccess(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rt := &retryTransport{
+		base:   http.DefaultTransport,
+		policy: retryPolicy{maxRetries: 3, baseDelay: time.Millisecond},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	res, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want %d", res.StatusCode, http.StatusOK)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRateLimiterWaitGatesOnCapacity(t *testing.T) {
+	rl := newRateLimiter(1, 1)
+	defer rl.close()
+
+	if err := rl.wait(context.Background()); err != nil {
+		t.Fatalf("first wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := rl.wait(ctx); err == nil {
+		t.Fatal("second wait() with an exhausted bucket and a short timeout should have errored")
+	}
+}
+
+func TestRateLimiterWaitReturnsHourlyTokenOnCancel(t *testing.T) {
+	rl := newRateLimiter(1, 1)
+	defer rl.close()
+
+	// Drain the per-second bucket so the next wait() blocks there and times out, without
+	// ever consuming the hourly token it already took.
+	<-rl.perSecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := rl.wait(ctx); err == nil {
+		t.Fatal("wait() with a drained per-second bucket should have errored")
+	}
+
+	select {
+	case <-rl.perHour:
+	default:
+		t.Error("canceled wait() should have returned the hourly token it consumed")
+	}
+}
+
+func TestRateLimiterCloseIsIdempotent(t *testing.T) {
+	rl := newRateLimiter(1, 1)
+	rl.close()
+	rl.close() // must not panic on a second call
+}
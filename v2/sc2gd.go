@@ -28,3 +28,25 @@ func (c *Client) SC2LadderData(ctx context.Context, ladderID int) (*sc2gd.Ladder
 	)
 	return dat.(*sc2gd.Ladder), b, err
 }
+
+// SC2LadderDataBatch returns SC2 ladders for each of the given ladderIDs, fetched
+// concurrently via the Batch engine. Results are returned in the same order as ladderIDs.
+func (c *Client) SC2LadderDataBatch(ctx context.Context, ladderIDs []int) ([]*sc2gd.Ladder, error) {
+	ladders := make([]*sc2gd.Ladder, len(ladderIDs))
+
+	batch := c.Batch(ctx, BatchOptions{})
+	for i, ladderID := range ladderIDs {
+		ladders[i] = &sc2gd.Ladder{}
+		batch.Add(BatchRequest{
+			Path: fmt.Sprintf("/data/sc2/ladder/%d", ladderID),
+			Out:  ladders[i],
+		})
+	}
+
+	_, err := batch.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return ladders, nil
+}
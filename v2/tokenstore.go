@@ -0,0 +1,160 @@
+package blizzard
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenStore persists an *oauth2.Token so it survives process restarts. This matters most
+// for user-authenticated tokens obtained via the Authorization Code flow, since those carry
+// a refresh_token that would otherwise force the user through the consent screen again.
+type TokenStore interface {
+	Load() (*oauth2.Token, error)
+	Save(*oauth2.Token) error
+}
+
+// ErrNoToken is returned by a TokenStore's Load method when no token has been saved yet
+var ErrNoToken = errors.New("blizzard: no token in store")
+
+// MemoryTokenStore is a TokenStore that only keeps the token in memory, useful mainly for
+// tests or short-lived processes that still want the OnTokenRefresh callback.
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// NewMemoryTokenStore creates an empty MemoryTokenStore
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{}
+}
+
+// Load returns the last token saved, or ErrNoToken if none has been saved yet
+func (s *MemoryTokenStore) Load() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return nil, ErrNoToken
+	}
+
+	return s.token, nil
+}
+
+// Save stores tok in memory, replacing any previously saved token
+func (s *MemoryTokenStore) Save(tok *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = tok
+
+	return nil
+}
+
+// FileTokenStore is a TokenStore backed by a JSON file on disk
+type FileTokenStore struct {
+	Path string
+}
+
+// NewFileTokenStore creates a FileTokenStore that reads and writes tok as JSON at path
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+// Load reads and decodes the token at s.Path, returning ErrNoToken if the file does not
+// exist yet
+func (s *FileTokenStore) Load() (*oauth2.Token, error) {
+	b, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tok oauth2.Token
+	if err := json.Unmarshal(b, &tok); err != nil {
+		return nil, err
+	}
+
+	return &tok, nil
+}
+
+// Save writes tok to s.Path as JSON, creating or truncating the file as needed
+func (s *FileTokenStore) Save(tok *oauth2.Token) error {
+	b, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.Path, b, 0600)
+}
+
+// WithTokenStore sets the TokenStore used to persist tokens obtained through the
+// Authorization Code flow, and to seed the client credentials flow's token source from a
+// previously saved token (if it's still valid) so a process restart doesn't force an
+// unnecessary token fetch.
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *Client) {
+		c.tokenStore = store
+	}
+}
+
+// OnTokenRefresh registers a callback invoked with the new token every time the underlying
+// oauth2.TokenSource refreshes it. Applications with their own persistence layer can use
+// this instead of, or in addition to, WithTokenStore.
+func (c *Client) OnTokenRefresh(fn func(*oauth2.Token)) {
+	c.tokenRefreshHooks = append(c.tokenRefreshHooks, fn)
+}
+
+// persistingTokenSource wraps an oauth2.TokenSource, saving to store and invoking the
+// client's OnTokenRefresh hooks every time base hands back a token that differs from the
+// last one seen.
+type persistingTokenSource struct {
+	base   oauth2.TokenSource
+	store  TokenStore
+	client *Client
+	mu     sync.Mutex
+	last   string
+}
+
+func (s *persistingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	changed := tok.AccessToken != s.last
+	s.last = tok.AccessToken
+	s.mu.Unlock()
+
+	if changed {
+		if s.store != nil {
+			_ = s.store.Save(tok)
+		}
+
+		for _, hook := range s.client.tokenRefreshHooks {
+			hook(tok)
+		}
+	}
+
+	return tok, nil
+}
+
+// withTokenPersistence wires a Client's TokenStore and OnTokenRefresh hooks into base
+func (c *Client) withTokenPersistence(base oauth2.TokenSource) oauth2.TokenSource {
+	if c.tokenStore == nil && len(c.tokenRefreshHooks) == 0 {
+		return base
+	}
+
+	return oauth2.ReuseTokenSource(nil, &persistingTokenSource{
+		base:   base,
+		store:  c.tokenStore,
+		client: c,
+	})
+}